@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAdminMuxServesMetricsAndPprof(t *testing.T) {
+	mux := newAdminMux()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("/metrics status = %d, want 200", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("/debug/pprof/ status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "pprof") {
+		t.Error("/debug/pprof/ body doesn't mention pprof, want the profile index page")
+	}
+}
@@ -0,0 +1,309 @@
+// Package config loads server settings by layering, from lowest to
+// highest precedence: built-in defaults, an optional YAML file, APP_*
+// environment variables, and command-line flags. main.go builds an
+// Overrides value from whichever flags the operator actually passed and
+// hands it to Load, so dev/staging/prod can share one binary without
+// rebuilding.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ServerConfig struct {
+	Port            string        `yaml:"port"`
+	Timeout         time.Duration `yaml:"timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type LoggingConfig struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+	File   string `yaml:"file"`
+}
+
+type TLSConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	CertFile        string   `yaml:"cert_file"`
+	KeyFile         string   `yaml:"key_file"`
+	AutocertDomains []string `yaml:"autocert_domains"`
+	AutocertCache   string   `yaml:"autocert_cache"`
+}
+
+// CORSConfig controls the CORS middleware, when Enabled.
+type CORSConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+}
+
+// RateLimitConfig controls the per-client-IP token-bucket rate limiter,
+// when Enabled.
+type RateLimitConfig struct {
+	Enabled           bool    `yaml:"enabled"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// FeaturesConfig toggles which middleware layers main.go's request chain
+// builds, so operators can turn each on or off without a rebuild.
+type FeaturesConfig struct {
+	RequestID bool            `yaml:"request_id"`
+	AccessLog string          `yaml:"access_log"` // "structured", "combined", or "off"
+	CORS      CORSConfig      `yaml:"cors"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Gzip      bool            `yaml:"gzip"`
+}
+
+// AdminConfig controls the admin server exposing pprof and Prometheus
+// metrics on a separate listener from the public one.
+type AdminConfig struct {
+	Addr string `yaml:"addr"` // empty disables the admin server
+}
+
+// ProfilingConfig controls the one-shot CPU/heap profile files written
+// around the process's lifetime, independent of the always-on admin pprof
+// endpoints.
+type ProfilingConfig struct {
+	CPUProfile string `yaml:"cpu_profile"` // empty disables CPU profiling
+	MemProfile string `yaml:"mem_profile"` // empty disables the heap profile
+}
+
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	TLS       TLSConfig       `yaml:"tls"`
+	Features  FeaturesConfig  `yaml:"features"`
+	Admin     AdminConfig     `yaml:"admin"`
+	Profiling ProfilingConfig `yaml:"profiling"`
+}
+
+// Default returns the settings the server starts with before any file,
+// env, or flag layer is applied.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			Timeout:         30 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Logging: LoggingConfig{
+			Format: "text",
+			Level:  "info",
+		},
+		TLS: TLSConfig{
+			AutocertCache: "autocert-cache",
+		},
+		Features: FeaturesConfig{
+			RequestID: true,
+			AccessLog: "structured",
+			Gzip:      true,
+			RateLimit: RateLimitConfig{
+				RequestsPerSecond: 5,
+				Burst:             10,
+			},
+		},
+	}
+}
+
+// Overrides carries the flags an operator actually set on the command
+// line. A nil field means "not set": that layer is left to the env/file/
+// default values beneath it. ConfigFile is handled separately since it
+// names the file layer itself rather than a value within it.
+type Overrides struct {
+	ConfigFile string
+
+	Port            *string
+	Timeout         *time.Duration
+	ShutdownTimeout *time.Duration
+	LogFormat       *string
+	LogLevel        *string
+	LogFile         *string
+	TLSEnabled      *bool
+	CertFile        *string
+	KeyFile         *string
+	AutocertDomains *string
+	AutocertCache   *string
+	AdminAddr       *string
+	CPUProfile      *string
+	MemProfile      *string
+}
+
+// Load builds a Config from defaults, then the YAML file named by
+// o.ConfigFile (if any), then APP_* environment variables, then o's
+// flag overrides, in increasing order of precedence. The merged result
+// is validated before it's returned.
+func Load(o Overrides) (Config, error) {
+	cfg := Default()
+
+	if o.ConfigFile != "" {
+		data, err := os.ReadFile(o.ConfigFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("config: reading %s: %w", o.ConfigFile, err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return Config{}, fmt.Errorf("config: parsing %s: %w", o.ConfigFile, err)
+		}
+	}
+
+	mergeEnv(&cfg)
+	mergeFlags(&cfg, o)
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func mergeEnv(cfg *Config) {
+	if v, ok := os.LookupEnv("APP_PORT"); ok {
+		cfg.Server.Port = v
+	}
+	if v, ok := os.LookupEnv("APP_TIMEOUT"); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Server.Timeout = d
+		}
+	}
+	if v, ok := os.LookupEnv("APP_SHUTDOWN_TIMEOUT"); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.Server.ShutdownTimeout = d
+		}
+	}
+	if v, ok := os.LookupEnv("APP_LOG_FORMAT"); ok {
+		cfg.Logging.Format = v
+	}
+	if v, ok := os.LookupEnv("APP_LOG_LEVEL"); ok {
+		cfg.Logging.Level = v
+	}
+	if v, ok := os.LookupEnv("APP_LOG_FILE"); ok {
+		cfg.Logging.File = v
+	}
+	if v, ok := os.LookupEnv("APP_TLS"); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.TLS.Enabled = b
+		}
+	}
+	if v, ok := os.LookupEnv("APP_TLS_CERT"); ok {
+		cfg.TLS.CertFile = v
+	}
+	if v, ok := os.LookupEnv("APP_TLS_KEY"); ok {
+		cfg.TLS.KeyFile = v
+	}
+	if v, ok := os.LookupEnv("APP_AUTOCERT_DOMAINS"); ok {
+		cfg.TLS.AutocertDomains = strings.Split(v, ",")
+	}
+	if v, ok := os.LookupEnv("APP_AUTOCERT_CACHE"); ok {
+		cfg.TLS.AutocertCache = v
+	}
+	if v, ok := os.LookupEnv("APP_ACCESS_LOG"); ok {
+		cfg.Features.AccessLog = v
+	}
+	if v, ok := os.LookupEnv("APP_CORS_ORIGINS"); ok {
+		cfg.Features.CORS.Enabled = true
+		cfg.Features.CORS.AllowedOrigins = strings.Split(v, ",")
+	}
+	if v, ok := os.LookupEnv("APP_RATE_LIMIT_RPS"); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Features.RateLimit.Enabled = true
+			cfg.Features.RateLimit.RequestsPerSecond = f
+		}
+	}
+	if v, ok := os.LookupEnv("APP_GZIP"); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Features.Gzip = b
+		}
+	}
+	if v, ok := os.LookupEnv("APP_ADMIN_ADDR"); ok {
+		cfg.Admin.Addr = v
+	}
+	if v, ok := os.LookupEnv("APP_CPU_PROFILE"); ok {
+		cfg.Profiling.CPUProfile = v
+	}
+	if v, ok := os.LookupEnv("APP_MEM_PROFILE"); ok {
+		cfg.Profiling.MemProfile = v
+	}
+}
+
+func mergeFlags(cfg *Config, o Overrides) {
+	if o.Port != nil {
+		cfg.Server.Port = *o.Port
+	}
+	if o.Timeout != nil {
+		cfg.Server.Timeout = *o.Timeout
+	}
+	if o.ShutdownTimeout != nil {
+		cfg.Server.ShutdownTimeout = *o.ShutdownTimeout
+	}
+	if o.LogFormat != nil {
+		cfg.Logging.Format = *o.LogFormat
+	}
+	if o.LogLevel != nil {
+		cfg.Logging.Level = *o.LogLevel
+	}
+	if o.LogFile != nil {
+		cfg.Logging.File = *o.LogFile
+	}
+	if o.TLSEnabled != nil {
+		cfg.TLS.Enabled = *o.TLSEnabled
+	}
+	if o.CertFile != nil {
+		cfg.TLS.CertFile = *o.CertFile
+	}
+	if o.KeyFile != nil {
+		cfg.TLS.KeyFile = *o.KeyFile
+	}
+	if o.AutocertDomains != nil {
+		cfg.TLS.AutocertDomains = strings.Split(*o.AutocertDomains, ",")
+	}
+	if o.AutocertCache != nil {
+		cfg.TLS.AutocertCache = *o.AutocertCache
+	}
+	if o.AdminAddr != nil {
+		cfg.Admin.Addr = *o.AdminAddr
+	}
+	if o.CPUProfile != nil {
+		cfg.Profiling.CPUProfile = *o.CPUProfile
+	}
+	if o.MemProfile != nil {
+		cfg.Profiling.MemProfile = *o.MemProfile
+	}
+}
+
+// Validate rejects merged configurations that would otherwise fail at
+// startup in confusing ways.
+func (c Config) Validate() error {
+	if c.Server.Port == "" {
+		return fmt.Errorf("config: server.port must not be empty")
+	}
+	if c.Server.Timeout <= 0 {
+		return fmt.Errorf("config: server.timeout must be positive")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("config: server.shutdown_timeout must be positive")
+	}
+	switch c.Logging.Format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("config: logging.format must be %q or %q, got %q", "text", "json", c.Logging.Format)
+	}
+	if c.TLS.Enabled && len(c.TLS.AutocertDomains) == 0 {
+		switch {
+		case c.TLS.CertFile == "" && c.TLS.KeyFile == "":
+			return fmt.Errorf("config: tls.enabled requires cert_file/key_file or autocert_domains")
+		case c.TLS.CertFile == "" || c.TLS.KeyFile == "":
+			return fmt.Errorf("config: tls.enabled requires both cert_file and key_file to be set")
+		}
+	}
+	switch c.Features.AccessLog {
+	case "structured", "combined", "off", "":
+	default:
+		return fmt.Errorf("config: features.access_log must be %q, %q, or %q, got %q", "structured", "combined", "off", c.Features.AccessLog)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	if cfg.Server.Port != "8080" {
+		t.Errorf("Server.Port = %q, want 8080", cfg.Server.Port)
+	}
+	if cfg.Logging.Format != "text" {
+		t.Errorf("Logging.Format = %q, want text", cfg.Logging.Format)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Default().Validate() = %v, want nil", err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: \"9090\"\nlogging:\n  format: json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_PORT", "9191")
+
+	flagPort := "9292"
+	cfg, err := Load(Overrides{ConfigFile: path, Port: &flagPort})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Server.Port != "9292" {
+		t.Errorf("Server.Port = %q, want the flag value to win over env and file", cfg.Server.Port)
+	}
+	if cfg.Logging.Format != "json" {
+		t.Errorf("Logging.Format = %q, want the file value to survive when nothing overrides it", cfg.Logging.Format)
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: \"9090\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_PORT", "9191")
+
+	cfg, err := Load(Overrides{ConfigFile: path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.Port != "9191" {
+		t.Errorf("Server.Port = %q, want the env value to win over the file", cfg.Server.Port)
+	}
+}
+
+func TestLoadRejectsInvalidMergedConfig(t *testing.T) {
+	badPort := ""
+	if _, err := Load(Overrides{Port: &badPort}); err == nil {
+		t.Error("Load() with an empty port override = nil error, want an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr bool
+	}{
+		{"defaults are valid", func(c *Config) {}, false},
+		{"empty port", func(c *Config) { c.Server.Port = "" }, true},
+		{"non-positive timeout", func(c *Config) { c.Server.Timeout = 0 }, true},
+		{"non-positive shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, true},
+		{"bad logging format", func(c *Config) { c.Logging.Format = "xml" }, true},
+		{"bad access log", func(c *Config) { c.Features.AccessLog = "bogus" }, true},
+		{"tls enabled with nothing configured", func(c *Config) {
+			c.TLS.Enabled = true
+		}, true},
+		{"tls enabled with only cert", func(c *Config) {
+			c.TLS.Enabled = true
+			c.TLS.CertFile = "cert.pem"
+		}, true},
+		{"tls enabled with only key", func(c *Config) {
+			c.TLS.Enabled = true
+			c.TLS.KeyFile = "key.pem"
+		}, true},
+		{"tls enabled with cert and key", func(c *Config) {
+			c.TLS.Enabled = true
+			c.TLS.CertFile = "cert.pem"
+			c.TLS.KeyFile = "key.pem"
+		}, false},
+		{"tls enabled with autocert domains", func(c *Config) {
+			c.TLS.Enabled = true
+			c.TLS.AutocertDomains = []string{"example.com"}
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Readiness tracks whether the service should currently receive traffic.
+// It starts ready and flips to false as soon as shutdown begins, so load
+// balancers polling /readyz stop routing new requests while in-flight ones
+// drain, without affecting the liveness check on /healthz.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// NewReadiness returns a Readiness that reports ready until SetReady(false)
+// is called.
+func NewReadiness() *Readiness {
+	r := &Readiness{}
+	r.ready.Store(true)
+	return r
+}
+
+// SetReady updates the readiness state reported by ReadyzHandler.
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// HealthzHandler is the liveness probe: it answers 200 as long as the
+// process is up and able to handle HTTP at all.
+func (r *Readiness) HealthzHandler(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// ReadyzHandler is the readiness probe: it answers 503 once shutdown has
+// started so upstream load balancers can drain traffic before the process
+// actually exits.
+func (r *Readiness) ReadyzHandler(w http.ResponseWriter, req *http.Request) {
+	if !r.ready.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadyzHandlerReflectsReadiness(t *testing.T) {
+	r := NewReadiness()
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	r.ReadyzHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("ReadyzHandler() before SetReady(false) = %d, want 200", rec.Code)
+	}
+
+	r.SetReady(false)
+
+	req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec = httptest.NewRecorder()
+	r.ReadyzHandler(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("ReadyzHandler() after SetReady(false) = %d, want 503", rec.Code)
+	}
+}
+
+func TestHealthzHandlerAlwaysOK(t *testing.T) {
+	r := NewReadiness()
+	r.SetReady(false) // liveness must stay healthy regardless of readiness
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	r.HealthzHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("HealthzHandler() = %d, want 200", rec.Code)
+	}
+}
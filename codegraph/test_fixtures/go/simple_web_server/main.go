@@ -1,25 +1,127 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"example.com/simple_web_server/config"
 	"example.com/simple_web_server/handlers"
+	"example.com/simple_web_server/metrics"
 	"example.com/simple_web_server/utils"
 )
 
 var (
+	configFile = flag.String("config", "", "Path to a YAML config file; flags and APP_* env vars take precedence over it")
+
 	port    = flag.String("port", "8080", "Server port")
 	timeout = flag.Duration("timeout", 30*time.Second, "Request timeout")
+
+	logFormat = flag.String("log-format", "text", "Log output format: text or json")
+	logLevel  = flag.String("log-level", "info", "Log level: debug, info, warn, or error")
+	logFile   = flag.String("log-file", "", "Write logs to this file instead of stdout (rotated at 100MB, 5 backups kept)")
+
+	shutdownTimeout = flag.Duration("shutdown-timeout", 15*time.Second, "How long to wait for in-flight requests to drain on shutdown")
+
+	tlsEnabled      = flag.Bool("tls", false, "Serve HTTPS instead of plain HTTP")
+	certFile        = flag.String("cert", "", "TLS certificate file (PEM), ignored when -autocert-domains is set")
+	keyFile         = flag.String("key", "", "TLS private key file (PEM), ignored when -autocert-domains is set")
+	autocertDomains = flag.String("autocert-domains", "", "Comma-separated domains to obtain certificates for via Let's Encrypt autocert; enables autocert instead of -cert/-key")
+	autocertCache   = flag.String("autocert-cache", "autocert-cache", "Directory for autocert's on-disk certificate cache")
+
+	adminAddr = flag.String("admin-addr", "", "Address for an admin server exposing pprof (/debug/pprof/) and Prometheus metrics (/metrics); disabled when empty")
+
+	cpuProfile = flag.String("cpuprofile", "", "Write a CPU profile to this file on exit")
+	memProfile = flag.String("memprofile", "", "Write a heap profile to this file on exit")
 )
+
+// loadConfig merges defaults, -config's YAML file, APP_* env vars, and
+// whichever flags were actually passed on the command line, in that order
+// of precedence.
+func loadConfig() (config.Config, error) {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	o := config.Overrides{ConfigFile: *configFile}
+	if set["port"] {
+		o.Port = port
+	}
+	if set["timeout"] {
+		o.Timeout = timeout
+	}
+	if set["shutdown-timeout"] {
+		o.ShutdownTimeout = shutdownTimeout
+	}
+	if set["log-format"] {
+		o.LogFormat = logFormat
+	}
+	if set["log-level"] {
+		o.LogLevel = logLevel
+	}
+	if set["log-file"] {
+		o.LogFile = logFile
+	}
+	if set["tls"] {
+		o.TLSEnabled = tlsEnabled
+	}
+	if set["cert"] {
+		o.CertFile = certFile
+	}
+	if set["key"] {
+		o.KeyFile = keyFile
+	}
+	if set["autocert-domains"] {
+		o.AutocertDomains = autocertDomains
+	}
+	if set["autocert-cache"] {
+		o.AutocertCache = autocertCache
+	}
+	if set["admin-addr"] {
+		o.AdminAddr = adminAddr
+	}
+	if set["cpuprofile"] {
+		o.CPUProfile = cpuProfile
+	}
+	if set["memprofile"] {
+		o.MemProfile = memProfile
+	}
+
+	return config.Load(o)
+}
+
 func main() {
 	flag.Parse()
 
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+
 	// Initialize utilities
-	logger := utils.NewLogger("server")
-	logger.Info("Starting server on port " + *port)
+	logger := utils.NewLoggerWithConfig("server", utils.LoggerConfig{
+		Format:      utils.LogFormat(cfg.Logging.Format),
+		Level:       utils.ParseLevel(cfg.Logging.Level),
+		File:        cfg.Logging.File,
+		MaxSizeMB:   100,
+		BackupCount: 5,
+		Compress:    true,
+	})
+	logger.RedirectStdLog()
+	logger.Info("starting server", "port", cfg.Server.Port)
+
+	stopCPUProfile := startCPUProfile(cfg.Profiling.CPUProfile, logger)
+	defer stopCPUProfile()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	readiness := handlers.NewReadiness()
 
 	// Set up routes
 	mux := http.NewServeMux()
@@ -29,47 +131,127 @@ func main() {
 	mux.HandleFunc("/api/health", handlers.HealthHandler)
 	mux.HandleFunc("/api/users", handlers.UsersHandler)
 	mux.HandleFunc("/api/items", handlers.ItemsHandler)
+	mux.HandleFunc("/healthz", readiness.HealthzHandler)
+	mux.HandleFunc("/readyz", readiness.ReadyzHandler)
 
 	// Create server with timeout
 	server := &http.Server{
-		Addr:         ":" + *port,
+		Addr:         ":" + cfg.Server.Port,
 		Handler:      mux,
-		ReadTimeout:  *timeout,
-		WriteTimeout: *timeout,
-		IdleTimeout:  *timeout * 2,
+		ReadTimeout:  cfg.Server.Timeout,
+		WriteTimeout: cfg.Server.Timeout,
+		IdleTimeout:  cfg.Server.Timeout * 2,
+		// Deliberately context.Background(), not the signal-derived ctx
+		// above: that one is canceled the instant SIGINT/SIGTERM arrives,
+		// which would cancel every in-flight request's context at the same
+		// moment instead of letting server.Shutdown drain them over
+		// -shutdown-timeout.
+		BaseContext: func(net.Listener) context.Context {
+			return context.Background()
+		},
+	}
+
+	// Middleware chain, built from config so each layer can be toggled
+	// without a rebuild.
+	mw := []utils.Middleware{utils.NewRecoveryMiddleware(logger)}
+	if cfg.Features.RequestID {
+		mw = append(mw, utils.RequestIDMiddleware)
+	}
+	if cfg.Features.CORS.Enabled {
+		mw = append(mw, utils.NewCORSMiddleware(utils.CORSConfig{
+			AllowedOrigins: cfg.Features.CORS.AllowedOrigins,
+			AllowedMethods: cfg.Features.CORS.AllowedMethods,
+		}))
+	}
+	if cfg.Features.RateLimit.Enabled {
+		mw = append(mw, utils.NewRateLimitMiddleware(cfg.Features.RateLimit.RequestsPerSecond, cfg.Features.RateLimit.Burst))
 	}
+	if cfg.Features.Gzip {
+		mw = append(mw, utils.NewGzipMiddleware())
+	}
+	if cfg.Admin.Addr != "" {
+		mw = append(mw, metrics.NewMiddleware())
+	}
+	switch cfg.Features.AccessLog {
+	case "structured":
+		mw = append(mw, utils.NewLoggingMiddleware(logger))
+	case "combined":
+		mw = append(mw, utils.NewCombinedLogMiddleware(os.Stdout))
+	}
+	server.Handler = utils.Chain(mw...).Then(mux)
 
-	// Middleware wrapper
-	wrappedHandler := utils.LoggingMiddleware(
-		utils.RecoveryMiddleware(mux),
-	)
-	server.Handler = wrappedHandler
+	var redirectServer *http.Server
+	if cfg.TLS.Enabled {
+		rs, err := setupTLS(server, cfg.TLS, logger)
+		if err != nil {
+			stopCPUProfile()
+			log.Fatalf("tls setup failed: %v", err)
+		}
+		redirectServer = rs
+	}
+
+	var adminServer *http.Server
+	if cfg.Admin.Addr != "" {
+		adminServer = &http.Server{Addr: cfg.Admin.Addr, Handler: newAdminMux()}
+		go func() {
+			logger.Info("admin server listening", "addr", cfg.Admin.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("admin server error", "error", err)
+			}
+		}()
+	}
 
 	// Start server
-	logger.Info("Server listening on http://localhost:" + *port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server error: %v", err)
+	go func() {
+		var err error
+		if cfg.TLS.Enabled {
+			if redirectServer != nil {
+				go func() {
+					if rerr := redirectServer.ListenAndServe(); rerr != nil && rerr != http.ErrServerClosed {
+						logger.Error("redirect server error", "error", rerr)
+					}
+				}()
+			}
+			certPath, keyPath := cfg.TLS.CertFile, cfg.TLS.KeyFile
+			if len(cfg.TLS.AutocertDomains) > 0 {
+				certPath, keyPath = "", ""
+			}
+			logger.Info("server listening", "addr", "https://localhost:"+cfg.Server.Port)
+			err = server.ListenAndServeTLS(certPath, keyPath)
+		} else {
+			logger.Info("server listening", "addr", "http://localhost:"+cfg.Server.Port)
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("server error", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	logger.Info("shutdown signal received, draining in-flight requests")
+	readiness.SetReady(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown failed", "error", err)
+	}
+	if redirectServer != nil {
+		if err := redirectServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("redirect server shutdown failed", "error", err)
+		}
 	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("admin server shutdown failed", "error", err)
+		}
+	}
+	logger.Info("server stopped")
+
+	writeMemProfile(cfg.Profiling.MemProfile, logger)
 }
 
-// Line 55
-// Line 56
-// Line 57
-// Line 58
-// Line 59
-// Line 60
-// Line 61
-// Line 62
-// Line 63
-// Line 64
-// Line 65
-// Line 66
-// Line 67
-// Line 68
-// Line 69
-// Line 70
-// Line 71
 func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("Initializing application...")
 }
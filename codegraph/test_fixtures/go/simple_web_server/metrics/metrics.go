@@ -0,0 +1,63 @@
+// Package metrics instruments the request chain with the RED method
+// (rate, errors, duration) plus response size and in-flight concurrency,
+// exposed to Prometheus via the admin server's /metrics endpoint.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"example.com/simple_web_server/utils"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests, by route, method, and status.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency, by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	responseSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_response_size_bytes",
+		Help:    "HTTP response size, by route and method.",
+		Buckets: prometheus.ExponentialBuckets(100, 10, 6),
+	}, []string{"route", "method"})
+
+	inFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being handled.",
+	})
+)
+
+// NewMiddleware returns a utils.Middleware that records request count,
+// latency, response size, and in-flight concurrency for every request the
+// chain handles.
+func NewMiddleware() utils.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			start := time.Now()
+			rec := utils.NewStatusRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			// net/http's default ServeMux doesn't expose the matched
+			// pattern after routing, so the request path stands in for
+			// "route" here.
+			route := r.URL.Path
+			requestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(rec.Status)).Inc()
+			requestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+			responseSize.WithLabelValues(route, r.Method).Observe(float64(rec.Bytes))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"runtime/pprof"
+
+	"example.com/simple_web_server/utils"
+)
+
+// startCPUProfile begins CPU profiling to path, returning a stop function
+// the caller must defer to flush and close the file. When path is empty it
+// returns a no-op, so callers can unconditionally defer the result.
+func startCPUProfile(path string, logger *utils.Logger) func() {
+	if path == "" {
+		return func() {}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logger.Error("cpuprofile: creating file", "path", path, "error", err)
+		return func() {}
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		logger.Error("cpuprofile: starting", "error", err)
+		f.Close()
+		return func() {}
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		f.Close()
+	}
+}
+
+// writeMemProfile writes a heap profile to path, if non-empty. Intended to
+// run once as the process shuts down.
+func writeMemProfile(path string, logger *utils.Logger) {
+	if path == "" {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		logger.Error("memprofile: creating file", "path", path, "error", err)
+		return
+	}
+	defer f.Close()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		logger.Error("memprofile: writing", "error", err)
+	}
+}
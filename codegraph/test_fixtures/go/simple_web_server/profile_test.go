@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/simple_web_server/utils"
+)
+
+func TestStartCPUProfileNoopOnEmptyPath(t *testing.T) {
+	stop := startCPUProfile("", utils.NewLogger("test"))
+	stop() // must not panic with no profile in progress
+}
+
+func TestStartCPUProfileWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cpu.prof")
+	stop := startCPUProfile(path, utils.NewLogger("test"))
+	stop()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("stat %s: %v, want the profile file to exist", path, err)
+	}
+}
+
+func TestStartCPUProfileBadPath(t *testing.T) {
+	stop := startCPUProfile(filepath.Join(t.TempDir(), "missing-dir", "cpu.prof"), utils.NewLogger("test"))
+	stop() // the returned stop func must still be safe to call
+}
+
+func TestWriteMemProfileNoopOnEmptyPath(t *testing.T) {
+	writeMemProfile("", utils.NewLogger("test")) // must not panic
+}
+
+func TestWriteMemProfileWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mem.prof")
+	writeMemProfile(path, utils.NewLogger("test"))
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("stat %s: %v, want the profile file to exist", path, err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+
+	"example.com/simple_web_server/config"
+	"example.com/simple_web_server/utils"
+)
+
+// setupTLS configures server for HTTPS and enables HTTP/2. Certificates come
+// either from the static cert/key files in cfg or, when cfg.AutocertDomains
+// is set, from Let's Encrypt via autocert. In the autocert case it returns a
+// second server that must be started on :80 to answer ACME HTTP-01
+// challenges and redirect everything else to HTTPS; otherwise it returns
+// nil.
+func setupTLS(server *http.Server, cfg config.TLSConfig, logger *utils.Logger) (*http.Server, error) {
+	server.TLSConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		},
+	}
+
+	if err := http2.ConfigureServer(server, &http2.Server{}); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.AutocertDomains) == 0 {
+		return nil, nil
+	}
+
+	certManager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+		Cache:      autocert.DirCache(cfg.AutocertCache),
+	}
+	server.TLSConfig.GetCertificate = certManager.GetCertificate
+	logger.Info("autocert enabled", "domains", cfg.AutocertDomains, "cache_dir", cfg.AutocertCache)
+
+	redirectServer := &http.Server{
+		Addr:    ":80",
+		Handler: certManager.HTTPHandler(http.HandlerFunc(redirectToHTTPS)),
+	}
+	return redirectServer, nil
+}
+
+// redirectToHTTPS sends plain-HTTP requests to their HTTPS equivalent;
+// autocert's HTTPHandler falls through to it for anything that isn't an
+// ACME challenge.
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	target := "https://" + r.Host + r.URL.RequestURI()
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// NewCombinedLogMiddleware returns a Middleware that writes one NCSA
+// Combined Log Format line per request to w -- the format most log
+// shippers and legacy dashboards already know how to parse, for operators
+// who'd rather not switch those over to the structured logger.
+func NewCombinedLogMiddleware(w io.Writer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := NewStatusRecorder(rw)
+			next.ServeHTTP(rec, r)
+
+			fmt.Fprintf(w, "%s - - [%s] \"%s %s %s\" %d %d %q %q\n",
+				clientIP(r),
+				start.Format("02/Jan/2006:15:04:05 -0700"),
+				r.Method, r.URL.RequestURI(), r.Proto,
+				rec.Status, rec.Bytes,
+				r.Referer(), r.UserAgent(),
+			)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "net/http"
+
+// Middleware wraps an http.Handler to add cross-cutting behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chained is an ordered list of middlewares built by Chain.
+type Chained []Middleware
+
+// Chain builds a Chained from the given middlewares, applied outermost
+// first, in the style of justinas/alice: Chain(a, b, c).Then(h) runs a,
+// then b, then c, then h.
+func Chain(mw ...Middleware) Chained {
+	return Chained(mw)
+}
+
+// Then wraps next with every middleware in the chain, outermost first.
+func (c Chained) Then(next http.Handler) http.Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		next = c[i](next)
+	}
+	return next
+}
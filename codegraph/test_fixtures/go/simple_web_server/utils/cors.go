@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSConfig configures NewCORSMiddleware's allowed origins and methods.
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+}
+
+// NewCORSMiddleware returns a Middleware that sets Access-Control-* headers
+// for requests from an allowed origin ("*" matches any) and answers
+// preflight OPTIONS requests directly instead of forwarding them.
+func NewCORSMiddleware(cfg CORSConfig) Middleware {
+	allowAll := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			allowAll = true
+		}
+		origins[o] = true
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" && (allowAll || origins[origin]) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+				if methods != "" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+				}
+				w.Header().Set("Access-Control-Allow-Headers", "*")
+			}
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
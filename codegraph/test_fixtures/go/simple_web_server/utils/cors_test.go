@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddlewareAllowedOrigin(t *testing.T) {
+	handler := NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"https://example.com"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want https://example.com", got)
+	}
+}
+
+func TestCORSMiddlewareDisallowedOrigin(t *testing.T) {
+	handler := NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"https://example.com"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want unset for a disallowed origin", got)
+	}
+}
+
+func TestCORSMiddlewareShortCircuitsPreflight(t *testing.T) {
+	called := false
+	handler := NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"*"}})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+	if called {
+		t.Error("next handler was called, want OPTIONS short-circuited before it")
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// NewGzipMiddleware compresses responses with gzip when the client
+// advertises support for it via Accept-Encoding.
+func NewGzipMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, writer: gz}, r)
+		})
+	}
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer *gzip.Writer
+}
+
+// stripContentLength removes a Content-Length the wrapped handler may have
+// set for the uncompressed body (e.g. http.ServeContent sets one during its
+// own ServeHTTP) -- left in place it would no longer match the
+// gzip-compressed bytes actually written. Called right before headers are
+// sent, so it catches a Content-Length set at any point during the handler's
+// execution.
+func (w *gzipResponseWriter) stripContentLength() {
+	w.Header().Del("Content-Length")
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	w.stripContentLength()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	w.stripContentLength()
+	return w.writer.Write(b)
+}
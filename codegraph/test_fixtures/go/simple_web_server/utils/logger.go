@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LogFormat selects the slog.Handler used by NewLoggerWithConfig.
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+// ParseLevel maps a flag-friendly string ("debug", "info", "warn", "error")
+// onto a slog.Level, defaulting to Info for unrecognized input.
+func ParseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// LoggerConfig controls how NewLoggerWithConfig builds its output sink.
+type LoggerConfig struct {
+	Format LogFormat
+	Level  slog.Level
+
+	// File, when non-empty, directs output to a rotating file instead of
+	// stdout. MaxSizeMB triggers rollover, BackupCount bounds how many
+	// rotated files are kept (oldest deleted first), and Compress gzips
+	// everything but the currently-active file.
+	File        string
+	MaxSizeMB   int
+	BackupCount int
+	Compress    bool
+}
+
+// Logger wraps an slog.Logger tagged with a component name, matching the
+// small surface the rest of the codebase relies on (Info/Warn/Error/Debug).
+type Logger struct {
+	slog *slog.Logger
+}
+
+// NewLogger returns a Logger with sane defaults: info level, text output to
+// stdout. Use NewLoggerWithConfig when the caller needs JSON output,
+// leveled filtering, or file rotation.
+func NewLogger(component string) *Logger {
+	return NewLoggerWithConfig(component, LoggerConfig{
+		Format: LogFormatText,
+		Level:  slog.LevelInfo,
+	})
+}
+
+// NewLoggerWithConfig builds a Logger from explicit settings, typically
+// sourced from the -log-format/-log-level/-log-file flags in main.go.
+func NewLoggerWithConfig(component string, cfg LoggerConfig) *Logger {
+	var w io.Writer = os.Stdout
+	if cfg.File != "" {
+		w = newRotatingWriter(cfg.File, cfg)
+	}
+
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+	var handler slog.Handler
+	if cfg.Format == LogFormatJSON {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return &Logger{slog: slog.New(handler).With("component", component)}
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.slog.Debug(msg, args...) }
+func (l *Logger) Info(msg string, args ...any)  { l.slog.Info(msg, args...) }
+func (l *Logger) Warn(msg string, args ...any)  { l.slog.Warn(msg, args...) }
+func (l *Logger) Error(msg string, args ...any) { l.slog.Error(msg, args...) }
+
+// With returns a Logger that attaches the given key/value pairs to every
+// record it emits, e.g. per-request loggers carrying a request ID.
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{slog: l.slog.With(args...)}
+}
+
+// Slog exposes the underlying *slog.Logger for callers that want to pass it
+// to slog-aware APIs directly.
+func (l *Logger) Slog() *slog.Logger { return l.slog }
+
+// RedirectStdLog points the standard library "log" package at this Logger,
+// so third-party code that still calls log.Println funnels into the same
+// sink instead of bypassing it. It replaces the log.SetFlags-based setup
+// that used to run in init().
+func (l *Logger) RedirectStdLog() {
+	bridge := slog.NewLogLogger(l.slog.Handler(), slog.LevelInfo)
+	log.SetOutput(bridge.Writer())
+	log.SetFlags(0)
+}
+
+// rotatingWriter is an io.Writer that rolls the destination file over once
+// it exceeds maxSizeMB, keeping at most backupCount rotated files and
+// optionally gzip-compressing them.
+type rotatingWriter struct {
+	mu          sync.Mutex
+	path        string
+	maxSizeMB   int
+	backupCount int
+	compress    bool
+
+	file *os.File
+	size int64
+}
+
+func newRotatingWriter(path string, cfg LoggerConfig) *rotatingWriter {
+	maxSizeMB := cfg.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	backupCount := cfg.BackupCount
+	if backupCount <= 0 {
+		backupCount = 5
+	}
+	w := &rotatingWriter{
+		path:        path,
+		maxSizeMB:   maxSizeMB,
+		backupCount: backupCount,
+		compress:    cfg.Compress,
+	}
+	if err := w.open(); err != nil {
+		// Fall back to stderr rather than panic; logging must never take
+		// the process down.
+		fmt.Fprintf(os.Stderr, "utils: opening log file %q: %v\n", path, err)
+	}
+	return w
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.Stderr.Write(p)
+	}
+
+	if w.size+int64(len(p)) > int64(w.maxSizeMB)*1024*1024 {
+		if err := w.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "utils: rotating log file %q: %v\n", w.path, err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	w.shiftBackups()
+
+	rotated := w.path + ".1"
+	if err := os.Rename(w.path, rotated); err != nil {
+		return err
+	}
+	if w.compress {
+		if err := gzipFile(rotated); err != nil {
+			fmt.Fprintf(os.Stderr, "utils: compressing rotated log %q: %v\n", rotated, err)
+		}
+	}
+
+	return w.open()
+}
+
+// shiftBackups renames path.N(.gz) -> path.N+1(.gz) for existing backups,
+// dropping anything beyond backupCount.
+func (w *rotatingWriter) shiftBackups() {
+	for i := w.backupCount; i >= 1; i-- {
+		src := backupName(w.path, i, w.compress)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if i >= w.backupCount {
+			os.Remove(src)
+			continue
+		}
+		dst := backupName(w.path, i+1, w.compress)
+		os.Rename(src, dst)
+	}
+}
+
+func backupName(path string, n int, compressed bool) string {
+	name := fmt.Sprintf("%s.%d", path, n)
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"DEBUG", slog.LevelDebug},
+		{"warn", slog.LevelWarn},
+		{"warning", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"info", slog.LevelInfo},
+		{"", slog.LevelInfo},
+		{"bogus", slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := ParseLevel(tt.in); got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRotatingWriterRotatesAndPrunesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w := newRotatingWriter(path, LoggerConfig{MaxSizeMB: 1, BackupCount: 2})
+
+	chunks := [][]byte{
+		bytes.Repeat([]byte("a"), 600*1024),
+		bytes.Repeat([]byte("b"), 600*1024),
+		bytes.Repeat([]byte("c"), 600*1024),
+		bytes.Repeat([]byte("d"), 600*1024),
+	}
+	for _, chunk := range chunks {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	assertFileIs := func(name string, want []byte) {
+		t.Helper()
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("reading %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got %d bytes, want %d matching bytes", name, len(got), len(want))
+		}
+	}
+
+	// Four 600KB writes against a 1MB threshold rotate three times, leaving the
+	// newest two backups plus the current file; the oldest chunk should
+	// have been pruned since backupCount is 2.
+	assertFileIs("app.log", chunks[3])
+	assertFileIs("app.log.1", chunks[2])
+	assertFileIs("app.log.2", chunks[1])
+
+	if _, err := os.Stat(filepath.Join(dir, "app.log.3")); err == nil {
+		t.Error("app.log.3 exists, want it pruned since backupCount is 2")
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewLoggingMiddleware returns a Middleware that writes a structured access
+// log through logger: one record per request carrying method, path,
+// status, response size, latency, remote address, and a per-request ID.
+// It reuses the ID RequestIDMiddleware attached to the request context, if
+// that middleware ran earlier in the chain, or generates one itself.
+func NewLoggingMiddleware(logger *Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			reqID := RequestID(r.Context())
+			if reqID == "" {
+				reqID = newRequestID()
+			}
+
+			rec := NewStatusRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			logger.Info("request handled",
+				"request_id", reqID,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.Status,
+				"bytes", rec.Bytes,
+				"latency_ms", time.Since(start).Milliseconds(),
+				"remote_addr", r.RemoteAddr,
+			)
+		})
+	}
+}
+
+func newRequestID() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// clientIP extracts the request's remote IP, stripping the port
+// net/http.Request.RemoteAddr normally carries.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateLimitIdleTTL       = 5 * time.Minute
+	rateLimitSweepInterval = time.Minute
+)
+
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimitMiddleware returns a Middleware enforcing a token-bucket
+// limit per client IP: rps requests per second sustained, with bursts up
+// to burst. Entries idle for longer than rateLimitIdleTTL are swept
+// periodically so a long-running process doesn't accumulate one limiter
+// per distinct client forever.
+func NewRateLimitMiddleware(rps float64, burst int) Middleware {
+	var mu sync.Mutex
+	limiters := make(map[string]*rateLimiterEntry)
+
+	go sweepRateLimiters(&mu, limiters)
+
+	limiterFor := func(ip string) *rate.Limiter {
+		mu.Lock()
+		defer mu.Unlock()
+		e, ok := limiters[ip]
+		if !ok {
+			e = &rateLimiterEntry{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+			limiters[ip] = e
+		}
+		e.lastSeen = time.Now()
+		return e.limiter
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiterFor(clientIP(r)).Allow() {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sweepRateLimiters drops limiter entries that haven't been used in
+// rateLimitIdleTTL, bounding memory growth on a long-running process with
+// high client churn. It runs for the life of the process.
+func sweepRateLimiters(mu *sync.Mutex, limiters map[string]*rateLimiterEntry) {
+	ticker := time.NewTicker(rateLimitSweepInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		sweepRateLimitersOnce(mu, limiters, time.Now())
+	}
+}
+
+// sweepRateLimitersOnce deletes entries whose lastSeen is older than
+// rateLimitIdleTTL relative to now, as of one tick. Split out from
+// sweepRateLimiters so the eviction logic can be tested without waiting on
+// the real TTL/ticker.
+func sweepRateLimitersOnce(mu *sync.Mutex, limiters map[string]*rateLimiterEntry, now time.Time) {
+	cutoff := now.Add(-rateLimitIdleTTL)
+	mu.Lock()
+	defer mu.Unlock()
+	for ip, e := range limiters {
+		if e.lastSeen.Before(cutoff) {
+			delete(limiters, ip)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddlewareDeniesPastBurst(t *testing.T) {
+	handler := NewRateLimitMiddleware(1, 2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.1:54321"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 2; i++ {
+		if got := do(); got != http.StatusOK {
+			t.Fatalf("request %d = %d, want 200 within burst", i, got)
+		}
+	}
+	if got := do(); got != http.StatusTooManyRequests {
+		t.Errorf("request past burst = %d, want 429", got)
+	}
+}
+
+func TestSweepRateLimitersOnceEvictsIdleEntries(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Now()
+	limiters := map[string]*rateLimiterEntry{
+		"idle":   {lastSeen: now.Add(-rateLimitIdleTTL - time.Second)},
+		"active": {lastSeen: now},
+	}
+
+	sweepRateLimitersOnce(&mu, limiters, now)
+
+	if _, ok := limiters["idle"]; ok {
+		t.Error("idle entry survived the sweep, want it evicted")
+	}
+	if _, ok := limiters["active"]; !ok {
+		t.Error("active entry was evicted, want it kept")
+	}
+}
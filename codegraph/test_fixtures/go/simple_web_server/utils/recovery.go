@@ -0,0 +1,20 @@
+package utils
+
+import "net/http"
+
+// NewRecoveryMiddleware returns a Middleware that catches panics from the
+// wrapped handler, logs them through logger at error level, and responds
+// with 500 instead of letting the connection die.
+func NewRecoveryMiddleware(logger *Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					logger.Error("panic recovered", "error", rec, "path", r.URL.Path)
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// RequestIDHeader is the header RequestIDMiddleware reads an inbound
+// request ID from, and echoes the resulting one on, so proxies and
+// clients can correlate logs across services.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID returns the ID RequestIDMiddleware stashed in ctx, or "" if
+// the middleware never ran.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestIDMiddleware assigns each request an ID, reusing one already
+// present on the inbound X-Request-ID header or generating a new one, and
+// makes it available via RequestID(r.Context()) and the response header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	})
+}
@@ -0,0 +1,29 @@
+package utils
+
+import "net/http"
+
+// StatusRecorder wraps an http.ResponseWriter to capture the status code
+// and byte count it produces, since the standard interface doesn't expose
+// either after the fact. Shared by the access-log and metrics middlewares.
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+	Bytes  int
+}
+
+// NewStatusRecorder wraps w, defaulting Status to 200 until WriteHeader is
+// called explicitly (matching net/http's own default).
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.Bytes += n
+	return n, err
+}